@@ -0,0 +1,90 @@
+package sse
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+var messageBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// MessageBatch accumulates the wire representation of multiple messages so they can
+// be written to an io.Writer with a single call to WriteTo. Treating a batch of
+// messages, rather than a single write, as the unit of stream communication turns what
+// would otherwise be one write (and, behind a flushing io.Writer, one flush) per
+// message into a single call - which matters in broadcast scenarios, where the same
+// messages are fanned out to many subscribers.
+//
+// The zero value is an empty batch, ready to use.
+type MessageBatch struct {
+	buffers     net.Buffers
+	bufs        []*bytes.Buffer
+	firstAppend time.Time
+}
+
+// Append serializes msg and adds it to the batch.
+func (b *MessageBatch) Append(msg *Message) {
+	buf, _ := messageBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	_, _ = msg.WriteTo(buf)
+
+	if len(b.bufs) == 0 {
+		b.firstAppend = time.Now()
+	}
+
+	b.bufs = append(b.bufs, buf)
+	b.buffers = append(b.buffers, buf.Bytes())
+}
+
+// Len returns the number of messages currently held by the batch.
+func (b *MessageBatch) Len() int {
+	return len(b.bufs)
+}
+
+// WriteTo writes every message in the batch to w, in the order they were appended.
+// If w's underlying type is one net.Buffers knows how to write directly - such as
+// *net.TCPConn - the kernel sees the whole batch as a single writev syscall instead of
+// one write per message.
+//
+// WriteTo does not clear the batch, so it can be written to multiple subscribers;
+// call Reset once it's no longer needed to release its buffers back to the pool.
+func (b *MessageBatch) WriteTo(w io.Writer) (int64, error) {
+	buffers := make(net.Buffers, len(b.buffers))
+	copy(buffers, b.buffers)
+	return buffers.WriteTo(w)
+}
+
+// Reset clears the batch, returning its internal buffers to a shared pool for reuse.
+func (b *MessageBatch) Reset() {
+	for _, buf := range b.bufs {
+		messageBufferPool.Put(buf)
+	}
+	b.bufs = b.bufs[:0]
+	b.buffers = b.buffers[:0]
+	b.firstAppend = time.Time{}
+}
+
+// FlushPolicy reports whether a MessageBatch should be flushed to its subscribers
+// instead of accumulating further messages. It is checked by the caller managing the
+// batch - typically after every Append - so it can coalesce bursts of messages
+// (e.g. ticks from several topics) into fewer writes without holding onto them
+// indefinitely.
+type FlushPolicy func(*MessageBatch) bool
+
+// FlushEvery returns a FlushPolicy that requests a flush once the batch holds n or
+// more messages.
+func FlushEvery(n int) FlushPolicy {
+	return func(b *MessageBatch) bool { return b.Len() >= n }
+}
+
+// FlushEveryDuration returns a FlushPolicy that requests a flush once d has elapsed
+// since the first message currently in the batch was appended.
+func FlushEveryDuration(d time.Duration) FlushPolicy {
+	return func(b *MessageBatch) bool {
+		return !b.firstAppend.IsZero() && time.Since(b.firstAppend) >= d
+	}
+}
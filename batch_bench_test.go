@@ -0,0 +1,127 @@
+package sse
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+)
+
+// newLoopbackConn returns a connected *net.TCPConn pair (the caller gets the client
+// side; the server side is drained and discarded), so net.Buffers.WriteTo has a real
+// writev-capable connection to batch onto, not just an in-memory buffer.
+func newLoopbackConn(b *testing.B) (net.Conn, func()) {
+	b.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	serverDone := make(chan struct{})
+	go func() {
+		defer close(serverDone)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return conn, func() {
+		_ = conn.Close()
+		_ = ln.Close()
+		<-serverDone
+	}
+}
+
+func makeBenchMessages(n int) []*Message {
+	msgs := make([]*Message, n)
+	for i := range msgs {
+		m := &Message{}
+		m.AppendData(`{"id":42,"type":"update","payload":{"ok":true,"items":[1,2,3,4,5]}}`)
+		msgs[i] = m
+	}
+	return msgs
+}
+
+func BenchmarkMessageBatch_PerMessage_TCP(b *testing.B) {
+	conn, cleanup := newLoopbackConn(b)
+	defer cleanup()
+
+	msgs := makeBenchMessages(32)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, m := range msgs {
+			if _, err := m.WriteTo(conn); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkMessageBatch_Batched_TCP(b *testing.B) {
+	conn, cleanup := newLoopbackConn(b)
+	defer cleanup()
+
+	msgs := makeBenchMessages(32)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var batch MessageBatch
+	for i := 0; i < b.N; i++ {
+		for _, m := range msgs {
+			batch.Append(m)
+		}
+		if _, err := batch.WriteTo(conn); err != nil {
+			b.Fatal(err)
+		}
+		batch.Reset()
+	}
+}
+
+func BenchmarkMessageBatch_PerMessage_Bufio(b *testing.B) {
+	msgs := makeBenchMessages(32)
+	w := bufio.NewWriter(io.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, m := range msgs {
+			if _, err := m.WriteTo(w); err != nil {
+				b.Fatal(err)
+			}
+		}
+		_ = w.Flush()
+	}
+}
+
+func BenchmarkMessageBatch_Batched_Bufio(b *testing.B) {
+	msgs := makeBenchMessages(32)
+	w := bufio.NewWriter(io.Discard)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var batch MessageBatch
+	for i := 0; i < b.N; i++ {
+		for _, m := range msgs {
+			batch.Append(m)
+		}
+		if _, err := batch.WriteTo(w); err != nil {
+			b.Fatal(err)
+		}
+		batch.Reset()
+		_ = w.Flush()
+	}
+}
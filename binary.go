@@ -0,0 +1,107 @@
+package sse
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"unsafe"
+
+	"github.com/tmaxmax/go-sse/internal/parser"
+)
+
+// BinaryCodec encodes binary data into a form that is safe to send over an event
+// stream - that is, one that never produces the newline characters (\r or \n) that
+// delimit SSE fields. See Message.AppendBinary for how it's used.
+//
+// Decoding is symmetric: call Decode with the same codec on each received data
+// field to recover the original bytes. On the client side, (*client.Event).DecodeBinary
+// does that for a received Event whose data is a single field.
+type BinaryCodec interface {
+	// Encode appends the encoding of src to dst and returns the extended buffer.
+	Encode(dst, src []byte) []byte
+	// Decode appends the decoding of src to dst and returns the extended buffer.
+	Decode(dst, src []byte) ([]byte, error)
+	// EncodedLen returns the length of the encoding of an input buffer of length n.
+	EncodedLen(n int) int
+}
+
+type base64Codec struct{ encoding *base64.Encoding }
+
+func (c base64Codec) Encode(dst, src []byte) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, c.encoding.EncodedLen(len(src)))...)
+	c.encoding.Encode(dst[n:], src)
+	return dst
+}
+
+func (c base64Codec) Decode(dst, src []byte) ([]byte, error) {
+	n := len(dst)
+	dst = append(dst, make([]byte, c.encoding.DecodedLen(len(src)))...)
+	m, err := c.encoding.Decode(dst[n:], src)
+	return dst[:n+m], err
+}
+
+func (c base64Codec) EncodedLen(n int) int { return c.encoding.EncodedLen(n) }
+
+// Base64StdCodec encodes binary data using standard base64 encoding, as defined in RFC 4648.
+var Base64StdCodec BinaryCodec = base64Codec{base64.StdEncoding}
+
+// Base64URLCodec encodes binary data using the URL-safe base64 encoding, as defined in RFC 4648.
+var Base64URLCodec BinaryCodec = base64Codec{base64.URLEncoding}
+
+type hexCodec struct{}
+
+func (hexCodec) Encode(dst, src []byte) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, hex.EncodedLen(len(src)))...)
+	hex.Encode(dst[n:], src)
+	return dst
+}
+
+func (hexCodec) Decode(dst, src []byte) ([]byte, error) {
+	n := len(dst)
+	dst = append(dst, make([]byte, hex.DecodedLen(len(src)))...)
+	m, err := hex.Decode(dst[n:], src)
+	return dst[:n+m], err
+}
+
+func (hexCodec) EncodedLen(n int) int { return hex.EncodedLen(n) }
+
+// HexCodec encodes binary data as lowercase hexadecimal.
+var HexCodec BinaryCodec = hexCodec{}
+
+// AppendBinary encodes each of chunks using codec and appends the result to the
+// message's event as a data field, the same way AppendData does for text - each
+// argument is encoded and chunked independently, so don't expect a decoder to be able
+// to concatenate their encoded forms back into one payload; decode each one on its own.
+//
+// Server-sent events are a text-only protocol - see AppendData's documentation for
+// why raw binary data can't be sent as-is. AppendBinary takes care of encoding it into
+// a newline-free representation using codec (Base64StdCodec, Base64URLCodec and
+// HexCodec are provided). Each chunk is encoded as a whole, so block-based codecs like
+// base64 only pad at the true end of the chunk; encoding it in smaller steps would risk
+// emitting padding in the middle of the data. The resulting text is then split into one
+// or more data fields using the usual chunking rules for multi-line data, same as
+// AppendData.
+func (e *Message) AppendBinary(codec BinaryCodec, chunks ...[]byte) {
+	for _, c := range chunks {
+		if len(c) == 0 {
+			continue
+		}
+
+		encoded := codec.Encode(make([]byte, 0, codec.EncodedLen(len(c))), c)
+		e.appendEncodedBinary(encoded)
+	}
+}
+
+func (e *Message) appendEncodedBinary(encoded []byte) {
+	// encoded was just allocated by AppendBinary and is never touched again, so viewing
+	// it as a string without copying is safe. The base64 and hex alphabets never produce
+	// \r or \n, so this yields a single field for the provided codecs; NextChunk splits
+	// the output of any other codec the same way appendText splits text.
+	s := *(*string)(unsafe.Pointer(&encoded))
+	for s != "" {
+		var content string
+		content, s, _ = parser.NextChunk(s)
+		e.chunks = append(e.chunks, chunk{content: content})
+	}
+}
@@ -0,0 +1,85 @@
+package sse
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// dataChunks returns the content of msg's plain data chunks (i.e. neither comments nor
+// extension fields), in the order they were appended.
+func dataChunks(msg *Message) []string {
+	var out []string
+	for i := range msg.chunks {
+		if msg.chunks[i].custom == "" && !msg.chunks[i].isComment {
+			out = append(out, msg.chunks[i].content)
+		}
+	}
+	return out
+}
+
+func TestAppendBinaryRoundTrip(t *testing.T) {
+	codecs := map[string]BinaryCodec{
+		"base64-std": Base64StdCodec,
+		"base64-url": Base64URLCodec,
+		"hex":        HexCodec,
+	}
+	sizes := []int{1, 2, 3, 4, 100, 1000}
+
+	for name, codec := range codecs {
+		codec := codec
+		t.Run(name, func(t *testing.T) {
+			for _, size := range sizes {
+				original := make([]byte, size)
+				rand.New(rand.NewSource(int64(size))).Read(original) //nolint:errcheck
+
+				msg := &Message{}
+				msg.AppendBinary(codec, original)
+
+				chunks := dataChunks(msg)
+				if len(chunks) != 1 {
+					t.Fatalf("size %d: got %d data fields, want 1", size, len(chunks))
+				}
+
+				decoded, err := codec.Decode(nil, []byte(chunks[0]))
+				if err != nil {
+					t.Fatalf("size %d: decode error: %v", size, err)
+				}
+				if !bytes.Equal(decoded, original) {
+					t.Fatalf("size %d: round-trip mismatch: got %x, want %x", size, decoded, original)
+				}
+			}
+		})
+	}
+}
+
+func TestAppendBinaryMultipleChunksIndependentlyDecodable(t *testing.T) {
+	a, b := []byte("hello"), []byte("world!!")
+
+	msg := &Message{}
+	msg.AppendBinary(Base64StdCodec, a, b)
+
+	chunks := dataChunks(msg)
+	if len(chunks) != 2 {
+		t.Fatalf("got %d data fields, want 2", len(chunks))
+	}
+
+	gotA, err := Base64StdCodec.Decode(nil, []byte(chunks[0]))
+	if err != nil || !bytes.Equal(gotA, a) {
+		t.Fatalf("first chunk: got %q, %v, want %q", gotA, err, a)
+	}
+
+	gotB, err := Base64StdCodec.Decode(nil, []byte(chunks[1]))
+	if err != nil || !bytes.Equal(gotB, b) {
+		t.Fatalf("second chunk: got %q, %v, want %q", gotB, err, b)
+	}
+}
+
+func TestAppendBinaryEmptyChunkIsNoOp(t *testing.T) {
+	msg := &Message{}
+	msg.AppendBinary(Base64StdCodec, nil, []byte{})
+
+	if chunks := dataChunks(msg); len(chunks) != 0 {
+		t.Fatalf("got %d data fields, want 0", len(chunks))
+	}
+}
@@ -0,0 +1,29 @@
+// Package client provides the types used to consume a server-sent events stream,
+// as opposed to package sse, which is used to produce one.
+package client
+
+import (
+	sse "github.com/tmaxmax/go-sse"
+)
+
+// Event is a single message received from an event stream, as delivered to a
+// channel passed to Connection.SubscribeMessages. LastEventID and Type mirror
+// the id and event fields of the message that produced it; Data holds the
+// joined content of its data field(s).
+type Event struct {
+	LastEventID string
+	Type        string
+	Data        string
+}
+
+// DecodeBinary decodes the event's Data field using codec, reversing the encoding
+// that Message.AppendBinary applies on the server. It's the client-side counterpart
+// that makes AppendBinary's encoding symmetric: encode with a BinaryCodec on the
+// way out, decode with the same one on the way in.
+//
+// Data is decoded as one payload, so this recovers the bytes of an event whose data
+// came from a single AppendBinary chunk; an event carrying several chunks, which
+// arrive joined by LF, is not split into its parts.
+func (e *Event) DecodeBinary(codec sse.BinaryCodec) ([]byte, error) {
+	return codec.Decode(nil, []byte(e.Data))
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	sse "github.com/tmaxmax/go-sse"
+)
+
+func TestEventDecodeBinaryRoundTrip(t *testing.T) {
+	original := []byte("hello, binary world!")
+	encoded := sse.Base64StdCodec.Encode(nil, original)
+
+	ev := &Event{Data: string(encoded)}
+
+	decoded, err := ev.DecodeBinary(sse.Base64StdCodec)
+	if err != nil {
+		t.Fatalf("DecodeBinary: %v", err)
+	}
+	if !bytes.Equal(decoded, original) {
+		t.Fatalf("got %q, want %q", decoded, original)
+	}
+}
@@ -0,0 +1,260 @@
+package sse
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressionLevel controls the trade-off between CPU cost and the size reduction
+// achieved by CompressionMiddleware. It mirrors the levels accepted by compress/gzip.
+type CompressionLevel int
+
+// Compression levels understood by CompressionMiddleware. CompressionDefault is a
+// good starting point for most event streams; CompressionBestSpeed trades ratio for
+// lower latency, which matters more for a protocol where every byte should reach the
+// client as soon as possible.
+const (
+	CompressionDefault   CompressionLevel = CompressionLevel(gzip.DefaultCompression)
+	CompressionBestSpeed CompressionLevel = CompressionLevel(gzip.BestSpeed)
+)
+
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+type compressionConfig struct {
+	level   CompressionLevel
+	minSize int
+}
+
+// CompressionOption configures the behavior of CompressionMiddleware.
+type CompressionOption func(*compressionConfig)
+
+// WithCompressionLevel sets the compression level used by CompressionMiddleware.
+// The default is CompressionDefault.
+func WithCompressionLevel(level CompressionLevel) CompressionOption {
+	return func(c *compressionConfig) { c.level = level }
+}
+
+// WithMinCompressSize sets a threshold, in bytes, used to coalesce small events
+// instead of paying the fixed cost of a compressor flush (a sync-flush marker, plus a
+// network flush) for each one individually. Every byte is still compressed through the
+// same gzip/deflate member - see the compressionResponseWriter.Flush doc for how the
+// threshold is applied. The default is 0, which flushes after every event, same as if
+// this middleware wasn't used.
+func WithMinCompressSize(n int) CompressionOption {
+	return func(c *compressionConfig) { c.minSize = n }
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, int(CompressionDefault))
+		return w
+	},
+}
+
+var zlibWriterPool = sync.Pool{
+	New: func() any {
+		w, _ := zlib.NewWriterLevel(io.Discard, int(CompressionDefault))
+		return w
+	},
+}
+
+type flusher interface {
+	io.Writer
+	Flush() error
+}
+
+// getCompressor returns a compressor for the given encoding, reusing a pooled one
+// when the requested level is CompressionDefault. Other levels are rare enough (they
+// are normally fixed for the lifetime of the middleware) that allocating a fresh
+// writer for them isn't worth a pool per level.
+//
+// "deflate" is implemented with compress/zlib, not compress/flate: the HTTP
+// Content-Encoding token "deflate" refers to the zlib-wrapped format (RFC 1950), which
+// has a header and an Adler-32 trailer. compress/flate alone only produces the raw
+// DEFLATE bitstream (RFC 1951), which most HTTP clients - browsers included - refuse
+// to decode when advertised as "deflate".
+func getCompressor(encoding string, w io.Writer, level CompressionLevel) flusher {
+	if level != CompressionDefault {
+		if encoding == encodingDeflate {
+			zw, _ := zlib.NewWriterLevel(w, int(level))
+			return zw
+		}
+		gw, _ := gzip.NewWriterLevel(w, int(level))
+		return gw
+	}
+
+	if encoding == encodingDeflate {
+		zw := zlibWriterPool.Get().(*zlib.Writer) //nolint:forcetypeassert
+		zw.Reset(w)
+		return zw
+	}
+
+	gw := gzipWriterPool.Get().(*gzip.Writer) //nolint:forcetypeassert
+	gw.Reset(w)
+	return gw
+}
+
+func putCompressor(encoding string, level CompressionLevel, f flusher) {
+	if level != CompressionDefault {
+		return
+	}
+	if encoding == encodingDeflate {
+		zlibWriterPool.Put(f.(*zlib.Writer)) //nolint:forcetypeassert
+	} else {
+		gzipWriterPool.Put(f.(*gzip.Writer)) //nolint:forcetypeassert
+	}
+}
+
+// compressionResponseWriter wraps an http.ResponseWriter so that every byte written to
+// it is compressed, through a single, continuously open gzip/deflate member, before
+// reaching the underlying connection.
+type compressionResponseWriter struct {
+	http.ResponseWriter
+
+	flusher  http.Flusher
+	config   *compressionConfig
+	encoding string
+
+	compressor flusher
+	level      CompressionLevel
+	pending    int // bytes written to the compressor since the last real Flush
+}
+
+// Write compresses p and buffers it inside the compressor; it is not guaranteed to
+// reach the client until Flush is called.
+func (w *compressionResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.compressor.Write(p)
+	w.pending += n
+	return n, err
+}
+
+// Flush flushes the compressor and the underlying connection, so the client receives
+// whatever has been written so far immediately instead of once the compression window
+// fills up - this is what lets SSE stay real-time under compression.
+//
+// The handler above is expected to call Flush once per event, the same way it would
+// without this middleware. If fewer than WithMinCompressSize bytes have been written
+// since the last real flush, this call is skipped and the pending bytes are coalesced
+// with the next event instead, to avoid paying for a sync-flush marker on every tiny
+// event; they are still guaranteed to reach the client once the handler finishes and
+// the compressor is closed.
+func (w *compressionResponseWriter) Flush() {
+	if w.pending < w.config.minSize {
+		return
+	}
+
+	_ = w.compressor.Flush()
+	w.flusher.Flush()
+	w.pending = 0
+}
+
+// Close finalizes the compressed member, flushing any bytes still pending, and
+// releases the pooled compressor. It must be called once ServeHTTP returns.
+func (w *compressionResponseWriter) Close() error {
+	err := w.compressor.(io.Closer).Close() //nolint:forcetypeassert
+	putCompressor(w.encoding, w.level, w.compressor)
+	return err
+}
+
+// negotiateEncoding parses an Accept-Encoding header value and picks gzip or deflate,
+// in that order of preference, ignoring (and preferring over an explicit refusal)
+// codings with a q-value of 0, as required by RFC 9110 section 12.5.3. An empty result means
+// neither coding is acceptable to the client.
+func negotiateEncoding(acceptEncoding string) string {
+	hasGzip, hasDeflate := false, false
+
+	for _, value := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(value), ";")
+		if parseQValue(params) == 0 {
+			continue
+		}
+
+		switch name {
+		case encodingGzip:
+			hasGzip = true
+		case encodingDeflate:
+			hasDeflate = true
+		}
+	}
+
+	if hasGzip {
+		return encodingGzip
+	}
+	if hasDeflate {
+		return encodingDeflate
+	}
+	return ""
+}
+
+// parseQValue extracts the q parameter from an Accept-Encoding coding's parameter
+// list (everything after the first ";"). It defaults to 1 when absent or malformed,
+// per the spec's "be liberal in what you accept" guidance - only an explicit, valid
+// q=0 should disable a coding.
+func parseQValue(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(name) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 1
+		}
+		return q
+	}
+	return 1
+}
+
+// CompressionMiddleware returns middleware that transparently compresses event streams
+// using gzip or deflate, whichever the client advertises via the Accept-Encoding
+// header. It always adds a "Vary: Accept-Encoding" header, and otherwise leaves the
+// response untouched when the client doesn't accept either, or when the
+// ResponseWriter doesn't implement http.Flusher.
+//
+// Compression is tailored to SSE's framing constraint: data must reach the client as
+// soon as it's produced, so the compressor is flushed after every call to Flush made
+// by the wrapped handler instead of waiting for its window to fill. Use
+// WithMinCompressSize to coalesce very small, frequent events (e.g. heartbeats)
+// instead of flushing each one individually.
+func CompressionMiddleware(opts ...CompressionOption) func(http.Handler) http.Handler {
+	config := &compressionConfig{level: CompressionDefault}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			fl, ok := w.(http.Flusher)
+			if encoding == "" || !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Encoding", encoding)
+			w.Header().Del("Content-Length")
+
+			cw := &compressionResponseWriter{
+				ResponseWriter: w,
+				flusher:        fl,
+				config:         config,
+				encoding:       encoding,
+				level:          config.level,
+				compressor:     getCompressor(encoding, w, config.level),
+			}
+			defer cw.Close() //nolint:errcheck
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
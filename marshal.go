@@ -0,0 +1,195 @@
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MarshalFormat selects the wire representation used by MarshalOptions.
+type MarshalFormat int
+
+const (
+	// FormatText is the standard SSE wire format, the same one produced by
+	// Message.MarshalText/WriteTo. It does not preserve Topic or ExpiresAt, as
+	// they aren't part of the protocol.
+	FormatText MarshalFormat = iota
+	// FormatJSON is a JSON representation that also preserves Topic and
+	// ExpiresAt (depending on MarshalOptions.IncludeTopic and IncludeExpiresAt),
+	// so a replay provider can persist a message - to Redis, to disk, wherever -
+	// and later restore it without losing server-side metadata. Its shape is:
+	//
+	//	{"id":..., "event":..., "data":[...], "comments":[...], "retry":..., "extra":[...], "topic":..., "expires_at":...}
+	//
+	// data and comments are stored as separate arrays, so the relative order
+	// between data and comment fields on the original message is NOT preserved -
+	// only the order within each array is. extra holds the message's extension
+	// fields (see Message.AppendField), each as {"name":..., "value":...}, in the
+	// order they were added; it is omitted when the message has none.
+	FormatJSON
+	// FormatBinary is reserved for a future, more compact binary representation
+	// meant for faster replay-store deserialization. It is not implemented yet;
+	// using it returns ErrUnsupportedFormat.
+	FormatBinary
+)
+
+// ErrUnsupportedFormat is returned by MarshalOptions when asked to marshal or
+// unmarshal using a MarshalFormat that isn't implemented yet.
+var ErrUnsupportedFormat = errors.New("sse: unsupported marshal format")
+
+// MarshalOptions marshals and unmarshals Messages the same way MarshalText and
+// UnmarshalText do, except it can also preserve Topic and ExpiresAt - which aren't
+// part of the SSE protocol, so the standard wire format has no place for them.
+// Replay providers that need to persist and later restore a message in full should
+// use this instead of MarshalText/UnmarshalText.
+type MarshalOptions struct {
+	// Format selects the wire representation to use. The zero value, FormatText,
+	// behaves exactly like MarshalText/UnmarshalText, and so never preserves
+	// Topic or ExpiresAt regardless of the fields below.
+	Format MarshalFormat
+	// IncludeTopic includes Topic in the marshalled representation. Only honored
+	// by FormatJSON.
+	IncludeTopic bool
+	// IncludeExpiresAt includes ExpiresAt in the marshalled representation. Only
+	// honored by FormatJSON.
+	IncludeExpiresAt bool
+}
+
+// jsonMessage is the stable, documented shape of a Message marshalled with FormatJSON.
+type jsonMessage struct {
+	ID        string     `json:"id,omitempty"`
+	Event     string     `json:"event,omitempty"`
+	Data      []string   `json:"data,omitempty"`
+	Comments  []string   `json:"comments,omitempty"`
+	Retry     string     `json:"retry,omitempty"`
+	Extra     []Field    `json:"extra,omitempty"`
+	Topic     string     `json:"topic,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Marshal serializes msg using the format and fields selected by o.
+func (o MarshalOptions) Marshal(msg *Message) ([]byte, error) {
+	switch o.Format {
+	case FormatText:
+		return msg.MarshalText()
+	case FormatJSON:
+		return o.marshalJSON(msg)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+func (o MarshalOptions) marshalJSON(msg *Message) ([]byte, error) {
+	jm := jsonMessage{
+		Event: msg.Name.String(),
+		ID:    msg.ID.String(),
+		Retry: msg.retryValue,
+	}
+
+	for i := range msg.chunks {
+		c := &msg.chunks[i]
+		switch {
+		case c.custom != "":
+			jm.Extra = append(jm.Extra, Field{Name: c.custom, Value: c.content})
+		case c.isComment:
+			jm.Comments = append(jm.Comments, c.content)
+		default:
+			jm.Data = append(jm.Data, c.content)
+		}
+	}
+
+	if o.IncludeTopic {
+		jm.Topic = msg.Topic
+	}
+	if o.IncludeExpiresAt && !msg.ExpiresAt.IsZero() {
+		expiresAt := msg.ExpiresAt
+		jm.ExpiresAt = &expiresAt
+	}
+
+	return json.Marshal(jm)
+}
+
+// Unmarshal parses p into msg using the format and fields selected by o. Previous
+// event fields on msg are overwritten, same as UnmarshalText; Topic and ExpiresAt
+// are overwritten too, but only when o and the input format carry them.
+//
+// All returned errors are of type UnmarshalError, except for malformed JSON input,
+// which is returned as-is from encoding/json, and ErrUnsupportedFormat for a format
+// that isn't implemented.
+func (o MarshalOptions) Unmarshal(p []byte, msg *Message) error {
+	switch o.Format {
+	case FormatText:
+		return msg.UnmarshalText(p)
+	case FormatJSON:
+		return o.unmarshalJSON(p, msg)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+func (o MarshalOptions) unmarshalJSON(p []byte, msg *Message) error {
+	var jm jsonMessage
+	if err := json.Unmarshal(p, &jm); err != nil {
+		return err
+	}
+
+	if err := validateRetry(jm.Retry); err != nil {
+		return err
+	}
+
+	msg.reset()
+
+	if jm.Event != "" {
+		msg.Name.value = jm.Event
+		msg.Name.set = true
+	}
+	if jm.ID != "" {
+		msg.ID.value = jm.ID
+		msg.ID.set = true
+	}
+	msg.retryValue = jm.Retry
+
+	for _, d := range jm.Data {
+		msg.chunks = append(msg.chunks, chunk{content: d})
+	}
+	for _, c := range jm.Comments {
+		msg.chunks = append(msg.chunks, chunk{content: c, isComment: true})
+	}
+	for _, f := range jm.Extra {
+		if validateFieldName(f.Name) != nil {
+			continue
+		}
+		msg.chunks = append(msg.chunks, chunk{content: f.Value, custom: f.Name})
+	}
+
+	if o.IncludeTopic {
+		msg.Topic = jm.Topic
+	}
+	if o.IncludeExpiresAt && jm.ExpiresAt != nil {
+		msg.ExpiresAt = *jm.ExpiresAt
+	}
+
+	return nil
+}
+
+// validateRetry checks that retry holds the same thing UnmarshalText requires of the
+// standard retry field: nothing but ASCII digits.
+func validateRetry(retry string) error {
+	i := strings.IndexFunc(retry, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if i == -1 {
+		return nil
+	}
+
+	r, _ := utf8.DecodeRuneInString(retry[i:])
+
+	return &UnmarshalError{
+		FieldName:  "retry",
+		FieldValue: retry,
+		Reason:     fmt.Errorf("contains character %q, which is not an ASCII digit", r),
+	}
+}
@@ -0,0 +1,104 @@
+package sse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMarshalOptionsJSONRoundTrip(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	msg := &Message{Topic: "news", ExpiresAt: expiresAt}
+	msg.AppendData("line one", "line two")
+	msg.Comment("a comment")
+	msg.SetRetry(5 * time.Second)
+	msg.Name.value, msg.Name.set = "update", true
+	msg.ID.value, msg.ID.set = "42", true
+	if err := msg.AppendField("channel", "general"); err != nil {
+		t.Fatalf("AppendField: %v", err)
+	}
+
+	opts := MarshalOptions{Format: FormatJSON, IncludeTopic: true, IncludeExpiresAt: true}
+
+	data, err := opts.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got Message
+	if err := opts.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Topic != msg.Topic {
+		t.Errorf("Topic = %q, want %q", got.Topic, msg.Topic)
+	}
+	if !got.ExpiresAt.Equal(msg.ExpiresAt) {
+		t.Errorf("ExpiresAt = %v, want %v", got.ExpiresAt, msg.ExpiresAt)
+	}
+	if got.Name.String() != "update" {
+		t.Errorf("Name = %q, want %q", got.Name.String(), "update")
+	}
+	if got.ID.String() != "42" {
+		t.Errorf("ID = %q, want %q", got.ID.String(), "42")
+	}
+	if got.retryValue != "5000" {
+		t.Errorf("retryValue = %q, want %q", got.retryValue, "5000")
+	}
+
+	extra := got.ExtraFields()
+	if len(extra) != 1 || extra[0].Name != "channel" || extra[0].Value != "general" {
+		t.Errorf("ExtraFields() = %+v, want [{channel general}]", extra)
+	}
+}
+
+func TestMarshalOptionsJSONWithoutTopicOrExpiry(t *testing.T) {
+	msg := &Message{Topic: "secret", ExpiresAt: time.Now()}
+	msg.AppendData("hello")
+
+	opts := MarshalOptions{Format: FormatJSON}
+
+	data, err := opts.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got Message
+	if err := opts.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Topic != "" {
+		t.Errorf("Topic = %q, want empty", got.Topic)
+	}
+	if !got.ExpiresAt.IsZero() {
+		t.Errorf("ExpiresAt = %v, want zero", got.ExpiresAt)
+	}
+}
+
+func TestMarshalOptionsJSONInvalidRetry(t *testing.T) {
+	opts := MarshalOptions{Format: FormatJSON}
+
+	var got Message
+	err := opts.Unmarshal([]byte(`{"retry":"12a3"}`), &got)
+
+	var unmarshalErr *UnmarshalError
+	if !errors.As(err, &unmarshalErr) {
+		t.Fatalf("Unmarshal error = %v, want *UnmarshalError", err)
+	}
+	if unmarshalErr.FieldName != "retry" {
+		t.Errorf("FieldName = %q, want %q", unmarshalErr.FieldName, "retry")
+	}
+}
+
+func TestMarshalOptionsUnsupportedFormat(t *testing.T) {
+	opts := MarshalOptions{Format: FormatBinary}
+
+	if _, err := opts.Marshal(&Message{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Marshal error = %v, want ErrUnsupportedFormat", err)
+	}
+	if err := opts.Unmarshal([]byte("{}"), &Message{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Unmarshal error = %v, want ErrUnsupportedFormat", err)
+	}
+}
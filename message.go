@@ -2,6 +2,7 @@ package sse
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -28,22 +29,43 @@ var (
 	fieldBytesComment = []byte(": ")
 )
 
+// fieldSeparator is the colon-space that follows a field name on the wire; it's the
+// same bytes as fieldBytesComment, which names the field-less comment form.
+var fieldSeparator = []byte(": ")
+
 type chunk struct {
 	content   string
+	custom    string // field name, for extension fields added through AppendField
 	isComment bool
 }
 
 var newline = []byte{'\n'}
 
 func (c *chunk) WriteTo(w io.Writer) (int64, error) {
-	name := fieldBytesData
-	if c.isComment {
-		name = fieldBytesComment
-	}
-	n, err := w.Write(name)
-	if err != nil {
-		return int64(n), err
+	var n int
+	var err error
+
+	if c.custom != "" {
+		n, err = writeString(w, c.custom)
+		if err != nil {
+			return int64(n), err
+		}
+		m, err := w.Write(fieldSeparator)
+		n += m
+		if err != nil {
+			return int64(n), err
+		}
+	} else {
+		name := fieldBytesData
+		if c.isComment {
+			name = fieldBytesComment
+		}
+		n, err = w.Write(name)
+		if err != nil {
+			return int64(n), err
+		}
 	}
+
 	m, err := writeString(w, c.content)
 	n += m
 	if err != nil {
@@ -71,6 +93,18 @@ type Message struct {
 	Topic     string
 	ExpiresAt time.Time
 
+	// MaxExtraFields caps the number of extension fields UnmarshalText will retain
+	// on the message. Zero means unlimited. Fields beyond the limit are dropped
+	// instead of causing an error, which matters when parsing a stream from an
+	// untrusted source. AppendField is unaffected, since the caller already
+	// controls how many fields it adds.
+	MaxExtraFields int
+	// MaxExtraFieldsSize caps the total size, in bytes, of the names and values of
+	// extension fields UnmarshalText will retain on the message. Zero means
+	// unlimited. As with MaxExtraFields, this only guards against hostile input;
+	// AppendField is unaffected.
+	MaxExtraFieldsSize int
+
 	ID   EventID
 	Name EventName
 
@@ -142,6 +176,73 @@ func (e *Message) SetRetry(duration time.Duration) {
 	e.retryValue = strconv.FormatInt(duration.Milliseconds(), 10)
 }
 
+// ErrInvalidFieldName is returned by AppendField when the given name cannot be used
+// as an SSE field name.
+var ErrInvalidFieldName = errors.New("sse: invalid field name")
+
+func validateFieldName(name string) error {
+	if name == "" || !utf8.ValidString(name) || strings.ContainsAny(name, ":\r\n") {
+		return ErrInvalidFieldName
+	}
+	switch name {
+	case "data", "event", "id", "retry":
+		// Reserved: WriteTo has no way to tell a custom field named e.g. "data" apart
+		// from a real data field, so it would come back from UnmarshalText as one,
+		// not as an extension field. Rejecting it here keeps ExtraFields symmetric.
+		return ErrInvalidFieldName
+	}
+	return nil
+}
+
+// AppendField creates a custom, non-standard field on the message's event, named name
+// and holding value. This is for SSE deployments that exchange additional, application-
+// specific fields alongside (or instead of) data - standard EventSource clients will
+// simply ignore fields they don't recognize, but a client built with this package can
+// read them back using ExtraFields.
+//
+// name must be non-empty, valid UTF-8, and must not contain a colon or a CR/LF byte,
+// per the field name grammar in the living standard; it also must not be one of the
+// four standard field names (data, event, id, retry), since those can't be told apart
+// from the real thing on the wire, which would break ExtraFields on the receiving end.
+// Violating any of this returns ErrInvalidFieldName. If value spans multiple lines, a
+// field is created for each line, same as AppendData does for data fields - and, also
+// the same as AppendData, an empty value appends nothing at all, since there's no line
+// to create a field from.
+func (e *Message) AppendField(name, value string) error {
+	if err := validateFieldName(name); err != nil {
+		return err
+	}
+
+	for value != "" {
+		var content string
+		content, value, _ = parser.NextChunk(value)
+		e.chunks = append(e.chunks, chunk{content: content, custom: name})
+	}
+
+	return nil
+}
+
+// Field is a single extension (non-standard) field of a Message, as added with
+// AppendField or found while unmarshaling an event.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ExtraFields returns the message's extension fields, in the order they were added or
+// parsed. It returns nil if the message has none.
+func (e *Message) ExtraFields() []Field {
+	var fields []Field
+
+	for i := range e.chunks {
+		if e.chunks[i].custom != "" {
+			fields = append(fields, Field{Name: e.chunks[i].custom, Value: e.chunks[i].content})
+		}
+	}
+
+	return fields
+}
+
 func (e *Message) writeMessageField(w io.Writer, f messageField, fieldBytes []byte) (int64, error) {
 	if !f.IsSet() {
 		return 0, nil
@@ -218,8 +319,8 @@ func (e *Message) WriteTo(w io.Writer) (int64, error) {
 // MarshalText writes the standard textual representation of the message's event. Marshalling and unmarshalling will
 // result in a message with an event that has the same fields; expiry time and topic will be lost.
 //
-// If you want to preserve everything, create your own custom marshalling logic.
-// For an example using encoding/json, see the top-level MessageCustomJSONMarshal example.
+// If you want to preserve Topic and ExpiresAt too - for example to persist a message in
+// a replay provider's store - use MarshalOptions with FormatJSON instead.
 //
 // Use the WriteTo method if you don't need the byte representation.
 //
@@ -280,12 +381,15 @@ func (e *Message) reset() {
 // (i.e. event, ID, comments, data, retry), but the Topic and ExpiresAt will be kept as is,
 // as these are not event fields.
 //
-// A method for marshalling and unmarshalling Messages together with their Topic and ExpiresAt
-// can be seen in the top-level example MessageCustomJSONMarshal.
+// To unmarshal a representation that also restores Topic and ExpiresAt, use
+// MarshalOptions with FormatJSON instead.
 //
-// Unmarshaling ignores fields with invalid names. If no valid fields are found,
-// an error is returned. For a field to be valid it must end in a newline - if the last
-// field of the event doesn't end in one, an error is returned.
+// Unknown field names are not discarded: they are kept as extension fields,
+// retrievable with ExtraFields, as long as the message's MaxExtraFields and
+// MaxExtraFieldsSize limits (if any) allow it. Only a blank line ends the event.
+// If no valid fields are found, an error is returned. For a field to be valid it
+// must end in a newline - if the last field of the event doesn't end in one, an
+// error is returned.
 //
 // All returned errors are of type UnmarshalError.
 func (e *Message) UnmarshalText(p []byte) error {
@@ -294,6 +398,8 @@ func (e *Message) UnmarshalText(p []byte) error {
 	s := parser.NewFieldParser(string(p))
 	s.KeepComments(true)
 
+	var extraCount, extraSize int
+
 loop:
 	for f := (parser.Field{}); s.Next(&f); {
 		switch f.Name {
@@ -323,8 +429,25 @@ loop:
 
 			e.ID.value = f.Value
 			e.ID.set = true
-		default: // event end
-			break loop
+		default:
+			if f.Name == "" { // blank line: event end
+				break loop
+			}
+
+			name := string(f.Name)
+			if validateFieldName(name) != nil {
+				continue
+			}
+			if e.MaxExtraFields > 0 && extraCount >= e.MaxExtraFields {
+				continue
+			}
+			if e.MaxExtraFieldsSize > 0 && extraSize+len(name)+len(f.Value) > e.MaxExtraFieldsSize {
+				continue
+			}
+
+			extraCount++
+			extraSize += len(name) + len(f.Value)
+			e.chunks = append(e.chunks, chunk{content: f.Value, custom: name})
 		}
 	}
 
@@ -338,8 +461,10 @@ loop:
 // Clone returns a copy of the message.
 func (e *Message) Clone() *Message {
 	return &Message{
-		Topic:     e.Topic,
-		ExpiresAt: e.ExpiresAt,
+		Topic:              e.Topic,
+		ExpiresAt:          e.ExpiresAt,
+		MaxExtraFields:     e.MaxExtraFields,
+		MaxExtraFieldsSize: e.MaxExtraFieldsSize,
 		// The first AppendData will trigger a reallocation.
 		// Already appended chunks cannot be modified/removed, so this is safe.
 		chunks:     e.chunks[:len(e.chunks):len(e.chunks)],
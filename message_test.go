@@ -0,0 +1,111 @@
+package sse
+
+import (
+	"testing"
+)
+
+func TestUnmarshalTextCapturesExtraField(t *testing.T) {
+	var msg Message
+	if err := msg.UnmarshalText([]byte("data: hi\nchannel: general\n\n")); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+
+	extra := msg.ExtraFields()
+	if len(extra) != 1 || extra[0] != (Field{Name: "channel", Value: "general"}) {
+		t.Fatalf("ExtraFields() = %+v, want [{channel general}]", extra)
+	}
+}
+
+func TestUnmarshalTextBlankLineEndsEventOnly(t *testing.T) {
+	var msg Message
+	// Two events back to back; only the first should be parsed.
+	input := "data: first\nchannel: one\n\ndata: second\nchannel: two\n\n"
+	if err := msg.UnmarshalText([]byte(input)); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+
+	if got := dataChunks(&msg); len(got) != 1 || got[0] != "first" {
+		t.Fatalf("data = %v, want [first]", got)
+	}
+	if extra := msg.ExtraFields(); len(extra) != 1 || extra[0].Value != "one" {
+		t.Fatalf("ExtraFields() = %+v, want a single field with value %q", extra, "one")
+	}
+}
+
+func TestUnmarshalTextMaxExtraFieldsDropsWithoutError(t *testing.T) {
+	msg := Message{MaxExtraFields: 1}
+	input := "data: hi\na: 1\nb: 2\nc: 3\n\n"
+
+	if err := msg.UnmarshalText([]byte(input)); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+
+	extra := msg.ExtraFields()
+	if len(extra) != 1 || extra[0].Name != "a" {
+		t.Fatalf("ExtraFields() = %+v, want exactly the first field, {a 1}", extra)
+	}
+}
+
+func TestUnmarshalTextMaxExtraFieldsSizeDropsWithoutError(t *testing.T) {
+	msg := Message{MaxExtraFieldsSize: len("a") + len("1")}
+	input := "data: hi\na: 1\nb: 22\n\n"
+
+	if err := msg.UnmarshalText([]byte(input)); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+
+	extra := msg.ExtraFields()
+	if len(extra) != 1 || extra[0].Name != "a" {
+		t.Fatalf("ExtraFields() = %+v, want only the field that fits the size cap", extra)
+	}
+}
+
+func TestUnmarshalTextInvalidFieldNameSkipped(t *testing.T) {
+	var msg Message
+	// \xff is not valid UTF-8, so the field name fails validation and is skipped,
+	// but the event is still parsed successfully (it has a valid data field).
+	input := "data: hi\n\xff: bad\n\n"
+
+	if err := msg.UnmarshalText([]byte(input)); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+
+	if extra := msg.ExtraFields(); len(extra) != 0 {
+		t.Fatalf("ExtraFields() = %+v, want none", extra)
+	}
+}
+
+func TestAppendFieldInvalidName(t *testing.T) {
+	var msg Message
+
+	for _, name := range []string{"", "has:colon", "has\nnewline", "has\rcr", "data", "event", "id", "retry"} {
+		if err := msg.AppendField(name, "value"); err != ErrInvalidFieldName {
+			t.Errorf("AppendField(%q, ...) error = %v, want ErrInvalidFieldName", name, err)
+		}
+	}
+}
+
+// AppendField rejects the standard field names outright, since a custom field with one
+// of these names is indistinguishable from the real thing once written to the wire -
+// UnmarshalText would read it back as the standard field, not via ExtraFields.
+func TestAppendFieldReservedNameNotInExtraFields(t *testing.T) {
+	var msg Message
+	if err := msg.AppendField("data", "hijacked"); err != ErrInvalidFieldName {
+		t.Fatalf("AppendField(%q, ...) error = %v, want ErrInvalidFieldName", "data", err)
+	}
+	if extra := msg.ExtraFields(); len(extra) != 0 {
+		t.Fatalf("ExtraFields() = %+v, want none", extra)
+	}
+}
+
+// AppendField mirrors AppendData/Comment: an empty value produces no field at all,
+// since there's nothing for NextChunk to split.
+func TestAppendFieldEmptyValueIsNoOp(t *testing.T) {
+	var msg Message
+	if err := msg.AppendField("channel", ""); err != nil {
+		t.Fatalf("AppendField: %v", err)
+	}
+	if extra := msg.ExtraFields(); len(extra) != 0 {
+		t.Fatalf("ExtraFields() = %+v, want none", extra)
+	}
+}